@@ -0,0 +1,258 @@
+// Copyright 2017 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ksonnet/ksonnet/metadata"
+	"github.com/ksonnet/ksonnet/prototype"
+	"github.com/ksonnet/ksonnet/prototype/matcher"
+	"github.com/spf13/cobra"
+)
+
+// fromFileParamName is the configmap prototype's parameter that
+// `inferredParams` populates from a `--from-file` source: a single
+// `key=value` pair, in the same form `objectToJsonnet` already accepts from
+// a repeated `--flag key=value` binding, where `key` is the file's base name
+// and `value` is its contents.
+const fromFileParamName = "data"
+
+const (
+	newAppExposeFlagName   = "expose"
+	newAppFromFileFlagName = "from-file"
+	newAppDryRunFlagName   = "dry-run"
+)
+
+func init() {
+	RootCmd.AddCommand(newAppCmd)
+}
+
+var newAppCmd = &cobra.Command{
+	Use:                "new-app <source> [parameter-flags]",
+	Short:              `Bootstrap a component by auto-selecting a prototype for an image, Git repo, or file`,
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, rawArgs []string) error {
+		source, expose, fromFile, dryRun, err := extractNewAppFlags(cmd, rawArgs)
+		if err != nil {
+			return err
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		manager, err := metadata.Find(metadata.AbsPath(cwd))
+		if err != nil {
+			return fmt.Errorf("Command can only be run in a ksonnet application directory:\n\n%v", err)
+		}
+
+		extProtos, err := manager.GetAllPrototypes()
+		if err != nil {
+			return err
+		}
+
+		protos, err := prototype.NewIndex(extProtos).List()
+		if err != nil {
+			return err
+		}
+
+		opts := matcher.Options{Expose: expose, FromFile: fromFile != ""}
+		kind := matcher.DetectInputKind(source, opts)
+		componentName := defaultComponentName(kind, source)
+		known, inferred, err := inferredParams(kind, source, fromFile, componentName)
+		if err != nil {
+			return err
+		}
+
+		proto, reason, err := matcher.Match(protos, kind, opts, known)
+		if err != nil {
+			return err
+		}
+
+		if err := bindPrototypeFlags(cmd, proto); err != nil {
+			return err
+		}
+		bindValuesFileFlag(cmd)
+		bindInteractiveFlags(cmd)
+		cmd.PersistentFlags().Bool(newAppExposeFlagName, expose, "Expose the component with an Ingress")
+		cmd.PersistentFlags().String(newAppFromFileFlagName, fromFile, "Treat the source as a file to materialize as a ConfigMap")
+		cmd.PersistentFlags().Bool(newAppDryRunFlagName, dryRun, "Print the generated component instead of writing it to components/")
+
+		explicit, err := explicitlySetFlagNames(proto, rawArgs)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		paramsByName := protoParamsByName(proto)
+		for name, val := range inferred {
+			if explicit[name] {
+				continue
+			}
+			param, ok := paramsByName[name]
+			if !ok {
+				continue
+			}
+			if err := setParamValue(flags, param, val); err != nil {
+				return fmt.Errorf("setting inferred parameter '%s': %v", name, err)
+			}
+		}
+
+		valuesFiles, err := extractValuesFiles(rawArgs)
+		if err != nil {
+			return err
+		}
+		if err := applyValuesFiles(flags, proto, valuesFiles, explicit); err != nil {
+			return err
+		}
+
+		cmd.DisableFlagParsing = false
+		if err := cmd.ParseFlags(rawArgs); err != nil {
+			return err
+		}
+
+		interactive, err := flags.GetBool(interactiveFlagName)
+		if err != nil {
+			return err
+		}
+		interactiveAll, err := flags.GetBool(interactiveAllFlagName)
+		if err != nil {
+			return err
+		}
+
+		params, err := getParameters(proto, flags, interactive, interactiveAll)
+		if err != nil {
+			return err
+		}
+
+		text, err := expandPrototype(proto, prototype.Jsonnet, params, componentName)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Selected prototype '%s': %s\n", proto.Name, reason)
+
+		if dryRun {
+			fmt.Println(text)
+			return nil
+		}
+
+		return manager.CreateComponent(componentName, text, params, prototype.Jsonnet)
+	},
+	Long: `Bootstrap a ksonnet component without picking a prototype by hand. ` + "`new-app`" + ` inspects
+` + "`source`" + ` -- a container image reference, a Git repository URL, or a local
+directory or file -- and automatically selects and instantiates the
+prototype that best fits it, the same way OpenShift's ` + "`oc new-app`" + ` picks a
+build strategy for you.
+
+Any required parameter the source doesn't already determine (for example
+` + "`image`" + `, for a Git repository or local directory source) can still be supplied with ` + "`--flag`" + `, ` + "`--values-file`" + `, or
+` + "`--interactive`" + `, exactly as with ` + "`ks prototype use`" + `.
+
+Use ` + "`--dry-run`" + ` to print the generated component instead of writing it to
+` + "`components/`" + `.`,
+
+	Example: `# Bootstrap a component from a bare image.
+ks new-app nginx:1.19
+
+# Bootstrap a component from an image, exposing it with an Ingress.
+ks new-app nginx:1.19 --expose
+
+# Bootstrap a ConfigMap from a local file.
+ks new-app --from-file=config/app.properties
+
+# Preview what would be generated without writing it to components/.
+ks new-app nginx:1.19 --dry-run`,
+}
+
+// extractNewAppFlags separates the positional source argument from
+// new-app's own flags (--expose, --from-file, --dry-run) using a prescan
+// flag set, the same trick `extractValuesFiles` uses: the source has to be
+// known before a prototype -- and therefore its flags -- can be chosen, so
+// the full flag set isn't registered on `cmd` yet when `rawArgs` is first
+// inspected here.
+func extractNewAppFlags(cmd *cobra.Command, rawArgs []string) (source string, expose bool, fromFile string, dryRun bool, err error) {
+	fs := pflag.NewFlagSet("new-app-prescan", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: true}
+	fs.BoolVar(&expose, newAppExposeFlagName, false, "")
+	fs.StringVar(&fromFile, newAppFromFileFlagName, "", "")
+	fs.BoolVar(&dryRun, newAppDryRunFlagName, false, "")
+	if err = fs.Parse(rawArgs); err != nil {
+		return "", false, "", false, err
+	}
+
+	args := fs.Args()
+	if fromFile == "" && len(args) < 1 {
+		return "", false, "", false, fmt.Errorf("Command 'new-app' requires a source (image, Git repo, or local path), or --from-file\n\n%s", cmd.UsageString())
+	}
+	if fromFile != "" {
+		return fromFile, expose, fromFile, dryRun, nil
+	}
+
+	return args[0], expose, fromFile, dryRun, nil
+}
+
+// defaultComponentName derives a component (and `name` parameter) from the
+// source itself, so a source alone is enough to bootstrap a component
+// without also requiring an explicit `--name`.
+func defaultComponentName(kind matcher.InputKind, source string) string {
+	if kind == matcher.Image {
+		base := path.Base(source)
+		if idx := strings.IndexAny(base, ":@"); idx >= 0 {
+			base = base[:idx]
+		}
+		return base
+	}
+
+	base := path.Base(strings.TrimRight(source, "/"))
+	return strings.TrimSuffix(base, ".git")
+}
+
+// inferredParams derives parameter values directly from the command line
+// input, the same way a user would fill in `--name`/`--image`/`--data` by
+// hand. It returns two views of the same inference: `coverage` reports only
+// which parameter names the source determines (all `matcher.Score` needs, to
+// judge how well a prototype's required parameters are covered); `values`
+// carries the actual values to apply to the chosen prototype's flags, keyed
+// the same way. Anything not covered here is left for the user to supply via
+// flags, --values-file, or --interactive.
+func inferredParams(kind matcher.InputKind, source, fromFile, componentName string) (coverage map[string]string, values map[string]interface{}, err error) {
+	coverage = map[string]string{"name": componentName}
+	values = map[string]interface{}{"name": componentName}
+
+	switch kind {
+	case matcher.Image:
+		coverage["image"] = source
+		values["image"] = source
+	case matcher.FilePath:
+		data, err := ioutil.ReadFile(fromFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading --from-file '%s': %v", fromFile, err)
+		}
+		key := path.Base(fromFile)
+		coverage[fromFileParamName] = key
+		values[fromFileParamName] = fmt.Sprintf("%s=%s", key, data)
+	}
+
+	return coverage, values, nil
+}
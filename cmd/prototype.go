@@ -16,10 +16,16 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/ghodss/yaml"
 	"github.com/spf13/pflag"
 
 	"github.com/ksonnet/ksonnet/metadata"
@@ -30,6 +36,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const valuesFileFlagName = "values-file"
+
+const (
+	interactiveFlagName    = "interactive"
+	interactiveAllFlagName = "interactive-all"
+)
+
 func init() {
 	RootCmd.AddCommand(prototypeCmd)
 	RootCmd.AddCommand(generateCmd)
@@ -38,8 +51,13 @@ func init() {
 	prototypeCmd.AddCommand(prototypeSearchCmd)
 	prototypeCmd.AddCommand(prototypeUseCmd)
 	prototypeCmd.AddCommand(prototypePreviewCmd)
+	prototypeCmd.AddCommand(prototypeApplyCmd)
+
+	prototypeApplyCmd.Flags().StringSliceP(applyManifestFlagName, "f", nil, "Path to a YAML or JSON manifest of prototype invocations; can be repeated")
 }
 
+const applyManifestFlagName = "filename"
+
 var prototypeCmd = &cobra.Command{
 	Use:   "prototype",
 	Short: `Instantiate, inspect, and get examples for ksonnet prototypes`,
@@ -254,7 +272,24 @@ var prototypePreviewCmd = &cobra.Command{
 			return err
 		}
 
-		bindPrototypeFlags(cmd, proto)
+		if err := bindPrototypeFlags(cmd, proto); err != nil {
+			return err
+		}
+		bindValuesFileFlag(cmd)
+		bindInteractiveFlags(cmd)
+
+		explicit, err := explicitlySetFlagNames(proto, rawArgs)
+		if err != nil {
+			return err
+		}
+
+		valuesFiles, err := extractValuesFiles(rawArgs)
+		if err != nil {
+			return err
+		}
+		if err := applyValuesFiles(cmd.Flags(), proto, valuesFiles, explicit); err != nil {
+			return err
+		}
 
 		cmd.DisableFlagParsing = false
 		err = cmd.ParseFlags(rawArgs)
@@ -278,7 +313,16 @@ var prototypePreviewCmd = &cobra.Command{
 			return fmt.Errorf("Incorrect number of arguments supplied to 'prototype preview'\n\n%s", cmd.UsageString())
 		}
 
-		params, err := getParameters(proto, flags)
+		interactive, err := flags.GetBool(interactiveFlagName)
+		if err != nil {
+			return err
+		}
+		interactiveAll, err := flags.GetBool(interactiveAllFlagName)
+		if err != nil {
+			return err
+		}
+
+		params, err := getParameters(proto, flags, interactive, interactiveAll)
 		if err != nil {
 			return err
 		}
@@ -356,7 +400,24 @@ var prototypeUseCmd = &cobra.Command{
 			return err
 		}
 
-		bindPrototypeFlags(cmd, proto)
+		if err := bindPrototypeFlags(cmd, proto); err != nil {
+			return err
+		}
+		bindValuesFileFlag(cmd)
+		bindInteractiveFlags(cmd)
+
+		explicit, err := explicitlySetFlagNames(proto, rawArgs)
+		if err != nil {
+			return err
+		}
+
+		valuesFiles, err := extractValuesFiles(rawArgs)
+		if err != nil {
+			return err
+		}
+		if err := applyValuesFiles(cmd.Flags(), proto, valuesFiles, explicit); err != nil {
+			return err
+		}
 
 		cmd.DisableFlagParsing = false
 		err = cmd.ParseFlags(rawArgs)
@@ -385,7 +446,16 @@ var prototypeUseCmd = &cobra.Command{
 			return fmt.Errorf("Command has too many arguments (takes a prototype name and a component name)\n\n%s", cmd.UsageString())
 		}
 
-		params, err := getParameters(proto, flags)
+		interactive, err := flags.GetBool(interactiveFlagName)
+		if err != nil {
+			return err
+		}
+		interactiveAll, err := flags.GetBool(interactiveAllFlagName)
+		if err != nil {
+			return err
+		}
+
+		params, err := getParameters(proto, flags, interactive, interactiveAll)
 		if err != nil {
 			return err
 		}
@@ -430,14 +500,516 @@ ks prototype use deployment nginx-depl \
   --image=nginx`,
 }
 
-func bindPrototypeFlags(cmd *cobra.Command, proto *prototype.SpecificationSchema) {
+// applyManifestEntry is one element of a `ks prototype apply` manifest: a
+// single prototype invocation to materialize into `components/`.
+type applyManifestEntry struct {
+	Prototype    string                 `json:"prototype"`
+	Component    string                 `json:"component"`
+	TemplateType string                 `json:"templateType"`
+	Params       map[string]interface{} `json:"params"`
+}
+
+// preparedComponent is the fully-expanded result of one manifest entry,
+// ready to be written with `manager.CreateComponent`.
+type preparedComponent struct {
+	name         string
+	text         string
+	params       map[string]string
+	templateType prototype.TemplateType
+}
+
+var prototypeApplyCmd = &cobra.Command{
+	Use:   "apply -f <manifest-file>",
+	Short: `Batch-instantiate prototypes from a manifest file`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("Command 'prototype apply' does not take arguments\n\n%s", cmd.UsageString())
+		}
+
+		manifestFiles, err := cmd.Flags().GetStringSlice(applyManifestFlagName)
+		if err != nil {
+			return err
+		}
+		if len(manifestFiles) == 0 {
+			return fmt.Errorf("Command 'prototype apply' requires at least one manifest file\n\n%s", cmd.UsageString())
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		manager, err := metadata.Find(metadata.AbsPath(cwd))
+		if err != nil {
+			return fmt.Errorf("Command can only be run in a ksonnet application directory:\n\n%v", err)
+		}
+
+		extProtos, err := manager.GetAllPrototypes()
+		if err != nil {
+			return err
+		}
+
+		entries, err := readApplyManifests(manifestFiles)
+		if err != nil {
+			return err
+		}
+
+		// Resolve, validate, and expand every entry before writing anything,
+		// so a problem anywhere in the manifest fails the whole run instead
+		// of leaving a partially-generated app.
+		prepared, err := prepareApplyEntries(entries, extProtos)
+		if err != nil {
+			return err
+		}
+
+		created := make([]string, 0, len(prepared))
+		for _, pc := range prepared {
+			if err := manager.CreateComponent(pc.name, pc.text, pc.params, pc.templateType); err != nil {
+				rollbackApplyEntries(manager, created)
+				return fmt.Errorf("creating component '%s': %v (rolled back %d previously created component(s))", pc.name, err, len(created))
+			}
+			created = append(created, pc.name)
+		}
+
+		fmt.Printf("Created %d component(s): %s\n", len(created), strings.Join(created, ", "))
+		return nil
+	},
+	Long: `Read a manifest of prototype invocations from` + " `--filename`/`-f` " + `and
+materialize all of them into` + " `components/` " + `in a single pass.
+
+The manifest is a YAML or JSON list of entries, each shaped like:
+
+    - prototype: simple-deployment
+      component: nginx-depl
+      templateType: jsonnet
+      params:
+        name: nginx
+        image: nginx:1.19
+        port: 80
+
+Every entry is resolved and expanded before any file is written; if any
+entry fails (an unknown prototype, a missing required parameter, a bad
+template type), no component from the manifest is written, and any
+component from an earlier entry in the same run is rolled back.`,
+
+	Example: `# Scaffold an entire app's worth of components from one manifest.
+ks prototype apply -f app.yaml
+
+# Merge entries from more than one manifest.
+ks prototype apply -f base.yaml -f overlay.yaml`,
+}
+
+// readApplyManifests loads and concatenates the manifest entries in `paths`,
+// in order.
+func readApplyManifests(paths []string) ([]applyManifestEntry, error) {
+	var entries []applyManifestEntry
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading manifest '%s': %v", path, err)
+		}
+
+		var fileEntries []applyManifestEntry
+		if err := yaml.Unmarshal(data, &fileEntries); err != nil {
+			return nil, fmt.Errorf("parsing manifest '%s': %v", path, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+// prepareApplyEntries resolves each entry's prototype, binds and validates
+// its parameters, and expands its template, entirely in memory. No
+// component is written to disk until every entry in `entries` has prepared
+// successfully.
+func prepareApplyEntries(entries []applyManifestEntry, extProtos prototype.SpecificationSchemas) ([]preparedComponent, error) {
+	prepared := make([]preparedComponent, 0, len(entries))
+	for i, entry := range entries {
+		if entry.Prototype == "" || entry.Component == "" {
+			return nil, fmt.Errorf("manifest entry %d is missing a 'prototype' and/or 'component' name", i)
+		}
+
+		proto, err := fundUniquePrototype(entry.Prototype, extProtos)
+		if err != nil {
+			return nil, fmt.Errorf("entry '%s': %v", entry.Component, err)
+		}
+
+		templateType := prototype.Jsonnet
+		if entry.TemplateType != "" {
+			templateType, err = prototype.ParseTemplateType(entry.TemplateType)
+			if err != nil {
+				return nil, fmt.Errorf("entry '%s': %v", entry.Component, err)
+			}
+		}
+
+		flags := pflag.NewFlagSet(entry.Component, pflag.ContinueOnError)
+		if err := bindPrototypeFlagSet(flags, proto); err != nil {
+			return nil, fmt.Errorf("entry '%s': %v", entry.Component, err)
+		}
+
+		known := protoParamsByName(proto)
+
+		for key, val := range entry.Params {
+			param, ok := known[key]
+			if !ok {
+				return nil, fmt.Errorf("entry '%s' sets unknown parameter '%s'", entry.Component, key)
+			}
+
+			if err := setParamValue(flags, param, val); err != nil {
+				return nil, fmt.Errorf("entry '%s', parameter '%s': %v", entry.Component, key, err)
+			}
+		}
+
+		params, err := getParameters(proto, flags, false, false)
+		if err != nil {
+			return nil, fmt.Errorf("entry '%s': %v", entry.Component, err)
+		}
+
+		text, err := expandPrototype(proto, templateType, params, entry.Component)
+		if err != nil {
+			return nil, fmt.Errorf("entry '%s': %v", entry.Component, err)
+		}
+
+		prepared = append(prepared, preparedComponent{
+			name:         entry.Component,
+			text:         text,
+			params:       params,
+			templateType: templateType,
+		})
+	}
+
+	return prepared, nil
+}
+
+// rollbackApplyEntries best-effort deletes every component in `created`,
+// restoring `components/` to its pre-`apply` state after a later entry in
+// the same manifest fails to write.
+func rollbackApplyEntries(manager metadata.Manager, created []string) {
+	for _, name := range created {
+		if err := manager.DeleteComponent(name); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to roll back component '%s': %v\n", name, err)
+		}
+	}
+}
+
+// bindValuesFileFlag registers the `--values-file`/`-f` flag, which lets a
+// user pre-populate prototype parameters from one or more YAML or JSON
+// files instead of (or in addition to) `--flag` arguments.
+func bindValuesFileFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSliceP(valuesFileFlagName, "f", nil, "Path to a YAML or JSON file of parameter values; can be repeated")
+}
+
+// extractValuesFiles pulls the `--values-file`/`-f` paths out of `rawArgs`
+// without requiring every other flag to be registered yet. This lets us load
+// the files and use them to seed parameter flag values before the real
+// `cmd.ParseFlags` call parses the rest of `rawArgs`, so that explicit
+// `--flag` arguments (parsed afterwards) take precedence over values loaded
+// from a file.
+func extractValuesFiles(rawArgs []string) ([]string, error) {
+	var paths []string
+	fs := pflag.NewFlagSet("values-file-prescan", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: true}
+	fs.StringSliceVarP(&paths, valuesFileFlagName, "f", nil, "")
+	if err := fs.Parse(rawArgs); err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// explicitlySetFlagNames reports which of `proto`'s parameters `rawArgs`
+// sets via an explicit `--flag`, without touching the real flag set.
+// `applyValuesFiles` uses this to skip those keys entirely rather than set
+// them first: for a StringSlice/Object parameter, `(*pflag.FlagSet).Set`
+// appends on every call after the first, so setting a values-file value and
+// then letting the command line "override" it would merge the two instead
+// of the command line winning outright.
+func explicitlySetFlagNames(proto *prototype.SpecificationSchema, rawArgs []string) (map[string]bool, error) {
+	scratch := pflag.NewFlagSet("explicit-flag-scan", pflag.ContinueOnError)
+	scratch.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: true}
+	if err := bindPrototypeFlagSet(scratch, proto); err != nil {
+		return nil, err
+	}
+	if err := scratch.Parse(rawArgs); err != nil {
+		return nil, err
+	}
+
+	explicit := map[string]bool{}
+	scratch.Visit(func(f *pflag.Flag) {
+		explicit[f.Name] = true
+	})
+	return explicit, nil
+}
+
+// protoParamsByName indexes proto's required and optional parameters by
+// name, for callers that need a parameter's declared type before setting
+// its flag (e.g. to decide whether `setParamValue` must reset a
+// StringSlice/Object-typed flag first).
+func protoParamsByName(proto *prototype.SpecificationSchema) map[string]prototype.ParamSchema {
+	known := map[string]prototype.ParamSchema{}
 	for _, param := range proto.RequiredParams() {
-		cmd.PersistentFlags().String(param.Name, "", param.Description)
+		known[param.Name] = param
+	}
+	for _, param := range proto.OptionalParams() {
+		known[param.Name] = param
+	}
+	return known
+}
+
+// applyValuesFiles loads each of `paths` (YAML or JSON) and, for every key
+// that names a known parameter of `proto`, sets the corresponding flag on
+// `flags` -- except for a key in `explicit`, which the command line is
+// about to set directly and so must win outright instead of being set here
+// first. Unknown keys are a hard error.
+func applyValuesFiles(flags *pflag.FlagSet, proto *prototype.SpecificationSchema, paths []string, explicit map[string]bool) error {
+	known := protoParamsByName(proto)
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading values file '%s': %v", path, err)
+		}
+
+		values := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("parsing values file '%s': %v", path, err)
+		}
+
+		for key, val := range values {
+			param, ok := known[key]
+			if !ok {
+				return fmt.Errorf("values file '%s' sets unknown parameter '%s'", path, key)
+			}
+			if explicit[key] {
+				continue
+			}
+
+			if err := setParamValue(flags, param, val); err != nil {
+				return fmt.Errorf("values file '%s', parameter '%s': %v", path, key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setParamValue assigns `val`, as decoded from a values file or manifest, to
+// `param`'s flag on `flags`, fully replacing whatever it already holds. For a
+// StringSlice/Object-typed flag this goes through `pflag.SliceValue.Replace`
+// rather than `(*pflag.FlagSet).Set`: `Set` appends after its first call, and
+// -- because the flag is a `StringArray` (see `bindPrototypeFlag`) -- even
+// `Set(name, "")` wouldn't clear it, since `StringArray.Set` appends its
+// argument verbatim instead of treating "" as "no elements" the way
+// `StringSlice`'s CSV parsing does.
+func setParamValue(flags *pflag.FlagSet, param prototype.ParamSchema, val interface{}) error {
+	if param.Type != prototype.StringSlice && param.Type != prototype.Object {
+		raw, err := valuesFileFlagString(val)
+		if err != nil {
+			return err
+		}
+		return flags.Set(param.Name, raw)
+	}
+
+	items, err := valuesFileSliceItems(val)
+	if err != nil {
+		return err
+	}
+
+	f := flags.Lookup(param.Name)
+	if f == nil {
+		return fmt.Errorf("no such flag '%s'", param.Name)
+	}
+	sv, ok := f.Value.(pflag.SliceValue)
+	if !ok {
+		return fmt.Errorf("flag '%s' does not support replacing its value", param.Name)
+	}
+	if err := sv.Replace(items); err != nil {
+		return err
+	}
+	// `Replace` bypasses `(*pflag.FlagSet).Set`, so mark the flag as set
+	// here; `getParameters` uses `Changed` to decide what is missing.
+	f.Changed = true
+	return nil
+}
+
+// valuesFileSliceItems splits a values-file value destined for a
+// StringSlice/Object-typed parameter into the individual elements that
+// `setParamValue` replaces the flag's value with, so that an element
+// containing a literal comma is never mistaken for a separator between
+// elements.
+func valuesFileSliceItems(val interface{}) ([]string, error) {
+	switch v := val.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				b, err := json.Marshal(item)
+				if err != nil {
+					return nil, err
+				}
+				s = string(b)
+			}
+			items = append(items, s)
+		}
+		return items, nil
+	case string:
+		return []string{v}, nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(b)}, nil
+	}
+}
+
+// valuesFileFlagString renders a scalar value decoded from a values file
+// into the string form expected by `(*pflag.FlagSet).Set`.
+func valuesFileFlagString(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+// bindInteractiveFlags registers the `--interactive`/`-i` and
+// `--interactive-all` flags. `--interactive` prompts for any required
+// parameter left unset after flags and values files are applied;
+// `--interactive-all` additionally prompts for unset optional parameters.
+func bindInteractiveFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolP(interactiveFlagName, "i", false, "Prompt for missing required parameters instead of failing")
+	cmd.PersistentFlags().Bool(interactiveAllFlagName, false, "With --interactive, also prompt for optional parameters left unset")
+}
+
+// stdinIsTerminal reports whether stdin looks like an interactive terminal.
+// `--interactive` is a no-op (rather than a hang) when it isn't, so scripted
+// and CI invocations behave the same as before this flag existed.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// promptForParam asks the user for `param`'s value on stdin, showing its
+// description, type, and default (if any), then sets it on `flags` via
+// `(*pflag.FlagSet).Set` -- the same path a `--flag` argument takes -- so the
+// value flows through `paramToJsonnet`'s type-aware conversion with no
+// extra code. A blank answer leaves the flag unset.
+func promptForParam(param prototype.ParamSchema, flags *pflag.FlagSet, reader *bufio.Reader) error {
+	def := ""
+	if param.Default != nil {
+		def = fmt.Sprintf(" [default: %s]", *param.Default)
+	}
+	fmt.Printf("%s (%s)%s\n  %s\n> ", param.Name, param.Type, def, param.Description)
+
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("reading value for parameter '%s': %v", param.Name, err)
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+
+	return flags.Set(param.Name, line)
+}
+
+func bindPrototypeFlags(cmd *cobra.Command, proto *prototype.SpecificationSchema) error {
+	return bindPrototypeFlagSet(cmd.PersistentFlags(), proto)
+}
+
+// bindPrototypeFlagSet registers every parameter of `proto` on `flags` using
+// the `pflag` kind that matches its declared type. It underlies
+// `bindPrototypeFlags` (for commands bound to a `*cobra.Command`) as well as
+// callers, like `ks prototype apply`, that bind a standalone
+// `*pflag.FlagSet` per manifest entry instead of a whole command.
+func bindPrototypeFlagSet(flags *pflag.FlagSet, proto *prototype.SpecificationSchema) error {
+	for _, param := range proto.RequiredParams() {
+		if err := bindPrototypeFlag(flags, param, nil); err != nil {
+			return err
+		}
 	}
 
 	for _, param := range proto.OptionalParams() {
-		cmd.PersistentFlags().String(param.Name, *param.Default, param.Description)
+		if err := bindPrototypeFlag(flags, param, param.Default); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindPrototypeFlag registers `param` on `flags` using the `pflag` kind that
+// matches its declared type, so that (e.g.) array and object parameters no
+// longer need to be hand-quoted as JSON strings on the command line.
+func bindPrototypeFlag(flags *pflag.FlagSet, param prototype.ParamSchema, def *string) error {
+	switch param.Type {
+	case prototype.Int:
+		d, err := parseIntDefault(param, def)
+		if err != nil {
+			return err
+		}
+		flags.Int(param.Name, d, param.Description)
+	case prototype.Bool:
+		d, err := parseBoolDefault(param, def)
+		if err != nil {
+			return err
+		}
+		flags.Bool(param.Name, d, param.Description)
+	case prototype.StringSlice, prototype.Object:
+		// Object parameters are bound the same way as StringSlice ones so
+		// that repeated `--flag key=value` invocations accumulate instead
+		// of overwriting one another; `ParamSchema.ToJsonnetSlice` is what
+		// tells the two apart when rendering to Jsonnet. `StringArray`,
+		// unlike `StringSlice`, never CSV-splits a value on commas, so a
+		// single `--flag` occurrence whose value contains a literal comma
+		// survives as one element instead of being shredded into two.
+		var d []string
+		if def != nil && *def != "" {
+			d = strings.Split(*def, ",")
+		}
+		flags.StringArray(param.Name, d, param.Description)
+	default:
+		var d string
+		if def != nil {
+			d = *def
+		}
+		flags.String(param.Name, d, param.Description)
+	}
+
+	return nil
+}
+
+func parseIntDefault(param prototype.ParamSchema, def *string) (int, error) {
+	if def == nil || *def == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(*def)
+	if err != nil {
+		return 0, fmt.Errorf("parameter '%s' has a non-integer default '%s'", param.Name, *def)
+	}
+	return v, nil
+}
+
+func parseBoolDefault(param prototype.ParamSchema, def *string) (bool, error) {
+	if def == nil || *def == "" {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(*def)
+	if err != nil {
+		return false, fmt.Errorf("parameter '%s' has a non-boolean default '%s'", param.Name, *def)
 	}
+	return v, nil
 }
 
 func expandPrototype(proto *prototype.SpecificationSchema, templateType prototype.TemplateType, params map[string]string, componentName string) (string, error) {
@@ -458,24 +1030,40 @@ func expandPrototype(proto *prototype.SpecificationSchema, templateType prototyp
 	return tm.Evaluate(params)
 }
 
-func getParameters(proto *prototype.SpecificationSchema, flags *pflag.FlagSet) (map[string]string, error) {
+// getParameters reads every parameter `proto` requires or accepts off
+// `flags`, converting each to a Jsonnet literal. When `interactive` is set
+// and stdin is a terminal, a required parameter left unset by flags or
+// `--values-file` is prompted for instead of immediately failing; when
+// `interactiveAll` is also set, unset optional parameters are prompted for
+// too.
+func getParameters(proto *prototype.SpecificationSchema, flags *pflag.FlagSet, interactive, interactiveAll bool) (map[string]string, error) {
+	promptable := interactive && stdinIsTerminal()
+	var reader *bufio.Reader
+	if promptable {
+		reader = bufio.NewReader(os.Stdin)
+	}
+
 	missingReqd := prototype.ParamSchemas{}
 	values := map[string]string{}
 	for _, param := range proto.RequiredParams() {
-		val, err := flags.GetString(param.Name)
-		if err != nil {
-			return nil, err
-		} else if val == "" {
+		if !flags.Changed(param.Name) && promptable {
+			if err := promptForParam(param, flags, reader); err != nil {
+				return nil, err
+			}
+		}
+
+		if !flags.Changed(param.Name) {
 			missingReqd = append(missingReqd, param)
+			continue
 		} else if _, ok := values[param.Name]; ok {
 			return nil, fmt.Errorf("Prototype '%s' has multiple parameters with name '%s'", proto.Name, param.Name)
 		}
 
-		quoted, err := param.Quote(val)
+		jsonnetVal, err := paramToJsonnet(param, flags)
 		if err != nil {
 			return nil, err
 		}
-		values[param.Name] = quoted
+		values[param.Name] = jsonnetVal
 	}
 
 	if len(missingReqd) > 0 {
@@ -483,23 +1071,65 @@ func getParameters(proto *prototype.SpecificationSchema, flags *pflag.FlagSet) (
 	}
 
 	for _, param := range proto.OptionalParams() {
-		val, err := flags.GetString(param.Name)
-		if err != nil {
-			return nil, err
-		} else if _, ok := values[param.Name]; ok {
+		if _, ok := values[param.Name]; ok {
 			return nil, fmt.Errorf("Prototype '%s' has multiple parameters with name '%s'", proto.Name, param.Name)
 		}
 
-		quoted, err := param.Quote(val)
+		if promptable && interactiveAll && !flags.Changed(param.Name) {
+			if err := promptForParam(param, flags, reader); err != nil {
+				return nil, err
+			}
+		}
+
+		jsonnetVal, err := paramToJsonnet(param, flags)
 		if err != nil {
 			return nil, err
 		}
-		values[param.Name] = quoted
+		values[param.Name] = jsonnetVal
 	}
 
 	return values, nil
 }
 
+// paramToJsonnet reads `param`'s current value off `flags`, using the
+// `pflag` getter that matches the kind of flag `bindPrototypeFlag` registered
+// for it, and converts it to a Jsonnet literal.
+func paramToJsonnet(param prototype.ParamSchema, flags *pflag.FlagSet) (string, error) {
+	switch param.Type {
+	case prototype.Int:
+		v, err := flags.GetInt(param.Name)
+		if err != nil {
+			return "", err
+		}
+		return param.ToJsonnet(strconv.Itoa(v))
+	case prototype.Bool:
+		v, err := flags.GetBool(param.Name)
+		if err != nil {
+			return "", err
+		}
+		return param.ToJsonnet(strconv.FormatBool(v))
+	case prototype.StringSlice, prototype.Object:
+		// Pass the already-split slice straight through to
+		// `ToJsonnetSlice` instead of comma-joining and letting it
+		// re-split, so an element containing a literal comma isn't
+		// shredded into extra elements on the round trip. `GetStringArray`
+		// (not `GetStringSlice`) is what makes that hold: a `StringSlice`
+		// flag CSV-splits on commas on every `Set` call, so even a single
+		// `--flag=a,b` occurrence would come back as two elements.
+		v, err := flags.GetStringArray(param.Name)
+		if err != nil {
+			return "", err
+		}
+		return param.ToJsonnetSlice(v)
+	default:
+		v, err := flags.GetString(param.Name)
+		if err != nil {
+			return "", err
+		}
+		return param.ToJsonnet(v)
+	}
+}
+
 func fundUniquePrototype(query string, extProtos prototype.SpecificationSchemas) (*prototype.SpecificationSchema, error) {
 	index := prototype.NewIndex(extProtos)
 
@@ -0,0 +1,94 @@
+// Copyright 2017 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ksonnet/ksonnet/prototype"
+)
+
+// These drive `bindPrototypeFlag` and `paramToJsonnet` through a real
+// `pflag.FlagSet` and `Parse`, rather than calling `ParamSchema.ToJsonnetSlice`
+// directly: `pflag.StringSlice` CSV-splits every value it's given, including
+// a single occurrence's value, so a hand-built `[]string` can look correct
+// while the actual flag-parsing round trip still shreds a literal comma.
+
+func TestParamToJsonnetStringSlicePreservesCommas(t *testing.T) {
+	param := prototype.ParamSchema{Name: "tag", Type: prototype.StringSlice}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := bindPrototypeFlag(flags, param, nil); err != nil {
+		t.Fatalf("bindPrototypeFlag: %v", err)
+	}
+	if err := flags.Parse([]string{"--tag=a,b", "--tag=c"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	got, err := paramToJsonnet(param, flags)
+	if err != nil {
+		t.Fatalf("paramToJsonnet: %v", err)
+	}
+	if want := `["a,b", "c"]`; got != want {
+		t.Errorf("paramToJsonnet(%q) = %q, want %q", []string{"--tag=a,b", "--tag=c"}, got, want)
+	}
+}
+
+func TestParamToJsonnetObjectFromRepeatedFlag(t *testing.T) {
+	param := prototype.ParamSchema{Name: "label", Type: prototype.Object}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := bindPrototypeFlag(flags, param, nil); err != nil {
+		t.Fatalf("bindPrototypeFlag: %v", err)
+	}
+	if err := flags.Parse([]string{"--label=a=1", "--label=b=2,3"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	got, err := paramToJsonnet(param, flags)
+	if err != nil {
+		t.Fatalf("paramToJsonnet: %v", err)
+	}
+	if got != `{"a":"1","b":"2,3"}` {
+		t.Errorf(`paramToJsonnet(--label=a=1 --label=b=2,3) = %q, want {"a":"1","b":"2,3"}`, got)
+	}
+}
+
+func TestSetParamValueReplacesRatherThanAppends(t *testing.T) {
+	param := prototype.ParamSchema{Name: "tag", Type: prototype.StringSlice}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := bindPrototypeFlag(flags, param, nil); err != nil {
+		t.Fatalf("bindPrototypeFlag: %v", err)
+	}
+	if err := flags.Parse([]string{"--tag=default1", "--tag=default2"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if err := setParamValue(flags, param, []interface{}{"x,y", "z"}); err != nil {
+		t.Fatalf("setParamValue: %v", err)
+	}
+
+	got, err := paramToJsonnet(param, flags)
+	if err != nil {
+		t.Fatalf("paramToJsonnet: %v", err)
+	}
+	if want := `["x,y", "z"]`; got != want {
+		t.Errorf("setParamValue did not fully replace the flag's prior value: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package matcher picks a prototype to instantiate for `ks new-app`, given
+// only the kind of source a user pointed it at (an image reference, a Git
+// repo, a local directory, or a file) and whatever parameter values can
+// already be inferred from that source.
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/prototype"
+)
+
+// InputKind classifies the source `ks new-app` was pointed at.
+type InputKind string
+
+const (
+	// Image indicates the source looks like a container image reference
+	// (e.g. `nginx:1.19`).
+	Image InputKind = "image"
+
+	// GitRepo indicates the source looks like a Git repository URL.
+	GitRepo InputKind = "gitRepo"
+
+	// LocalDir indicates the source is a local directory.
+	LocalDir InputKind = "localDir"
+
+	// FilePath indicates the source is a single file, e.g. supplied via
+	// `--from-file`, destined to become a ConfigMap.
+	FilePath InputKind = "file"
+)
+
+// Options carries hints, beyond the source string itself, that narrow which
+// prototype best fits it.
+type Options struct {
+	// Expose indicates the user passed `--expose`, and so a prototype that
+	// exposes the component (e.g. with an Ingress) should be preferred.
+	Expose bool
+
+	// FromFile indicates the source should be treated as a file, regardless
+	// of what it looks like, because the user passed `--from-file`.
+	FromFile bool
+}
+
+// DetectInputKind classifies `source` using simple, fast heuristics; it
+// never touches the filesystem or the network, so `ks new-app` can offer a
+// best-effort guess even when the image or repo isn't reachable yet.
+func DetectInputKind(source string, opts Options) InputKind {
+	switch {
+	case opts.FromFile:
+		return FilePath
+	case isGitURL(source):
+		return GitRepo
+	case strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") || strings.HasSuffix(source, "/"):
+		return LocalDir
+	default:
+		return Image
+	}
+}
+
+func isGitURL(source string) bool {
+	return strings.HasPrefix(source, "http://") ||
+		strings.HasPrefix(source, "https://") ||
+		strings.HasPrefix(source, "git@") ||
+		strings.HasSuffix(source, ".git")
+}
+
+// preferredSuffixes maps an input kind to the prototype name suffixes that
+// are the best fit for it, in descending order of preference. This plays
+// the same role for `new-app` that a user-supplied partial name plays for
+// `ks prototype use`'s suffix matching, just chosen automatically.
+var preferredSuffixes = map[InputKind][]string{
+	Image:    {"simple-deployment"},
+	GitRepo:  {"simple-deployment"},
+	LocalDir: {"simple-deployment"},
+	FilePath: {"configmap"},
+}
+
+var exposedImageSuffixes = []string{"deployment-exposed-with-ingress", "simple-deployment"}
+
+// Score ranks `proto` against `kind` and `known`, the parameter values
+// already inferred from the command line (e.g., an image reference parsed
+// out of the source). A prototype that declares required parameters none of
+// which are covered by `known` scores zero, since matching it would just
+// trade one "missing required parameters" failure for another.
+func Score(proto *prototype.SpecificationSchema, kind InputKind, opts Options, known map[string]string) int {
+	suffixes := preferredSuffixes[kind]
+	if kind == Image && opts.Expose {
+		suffixes = exposedImageSuffixes
+	}
+
+	score := 0
+	for i, suffix := range suffixes {
+		if strings.HasSuffix(proto.Name, suffix) {
+			score += (len(suffixes) - i) * 10
+			break
+		}
+	}
+	if score == 0 {
+		return 0
+	}
+
+	required := proto.RequiredParams()
+	covered := 0
+	for _, param := range required {
+		if _, ok := known[param.Name]; ok {
+			covered++
+		}
+	}
+	if len(required) > 0 && covered == 0 {
+		return 0
+	}
+
+	return score + covered
+}
+
+// Match picks the single best prototype in `protos` for `kind`, breaking
+// ties by required-parameter coverage, and returns it along with a short,
+// human-readable explanation of why it was chosen. It is an error for
+// nothing to score -- `new-app` should never silently fall back to an
+// arbitrary prototype.
+func Match(protos []*prototype.SpecificationSchema, kind InputKind, opts Options, known map[string]string) (*prototype.SpecificationSchema, string, error) {
+	var best *prototype.SpecificationSchema
+	bestScore := 0
+
+	for _, proto := range protos {
+		if score := Score(proto, kind, opts, known); score > bestScore {
+			best, bestScore = proto, score
+		}
+	}
+
+	if best == nil {
+		return nil, "", fmt.Errorf("no prototype matched a '%s' input", kind)
+	}
+
+	return best, fmt.Sprintf("detected a '%s' input, which matched prototype '%s'", kind, best.Name), nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package matcher
+
+import "testing"
+
+func TestDetectInputKind(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		opts   Options
+		want   InputKind
+	}{
+		{"from-file wins regardless of source shape", "nginx:1.19", Options{FromFile: true}, FilePath},
+		{"https git url", "https://github.com/ksonnet/ksonnet.git", Options{}, GitRepo},
+		{"http git url", "http://example.com/repo.git", Options{}, GitRepo},
+		{"scp-style git url", "git@github.com:ksonnet/ksonnet.git", Options{}, GitRepo},
+		{"dot-relative local dir", "./app", Options{}, LocalDir},
+		{"absolute local dir", "/srv/app", Options{}, LocalDir},
+		{"trailing slash local dir", "app/", Options{}, LocalDir},
+		{"bare image reference", "nginx:1.19", Options{}, Image},
+		{"image reference with registry", "gcr.io/my-project/my-image", Options{}, Image},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := DetectInputKind(c.source, c.opts)
+			if got != c.want {
+				t.Errorf("DetectInputKind(%q, %+v) = %q, want %q", c.source, c.opts, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2017 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package prototype
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ParamType identifies the kind of value a prototype parameter accepts. It
+// determines both the kind of `cobra`/`pflag` flag used to bind the
+// parameter on the command line, and how a raw flag value is rendered into
+// the Jsonnet literal that gets passed through to the template.
+type ParamType string
+
+const (
+	// String indicates a parameter whose value is emitted as a quoted
+	// Jsonnet string. This is the default, and preserves the behavior of
+	// prototypes that do not declare a type.
+	String ParamType = "string"
+
+	// Int indicates a parameter whose value is emitted as a bare Jsonnet
+	// number, and is bound to the command line as an integer flag.
+	Int ParamType = "int"
+
+	// Bool indicates a parameter whose value is emitted as a bare Jsonnet
+	// boolean, and is bound to the command line as a boolean flag.
+	Bool ParamType = "bool"
+
+	// StringSlice indicates a parameter that can be repeated on the command
+	// line (e.g., `--tag=foo --tag=bar`) and is emitted as a Jsonnet array
+	// of strings.
+	StringSlice ParamType = "stringSlice"
+
+	// Object indicates a parameter whose value is a Jsonnet object. It may
+	// be supplied as inline JSON, as a `@path/to/file.json` reference, or
+	// (when the underlying flag is repeated) as a set of `key=value` pairs.
+	Object ParamType = "object"
+)
+
+// ParamSchema defines a parameter that a prototype requires or optionally
+// accepts in order to be fully instantiated.
+type ParamSchema struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Default     *string   `json:"default,omitempty"`
+	Type        ParamType `json:"type,omitempty"`
+}
+
+// ParamSchemas is a list of ParamSchema.
+type ParamSchemas []ParamSchema
+
+// PrettyString pretty-prints a list of param schemas, indenting every line
+// by `indent`.
+func (schemas ParamSchemas) PrettyString(indent string) string {
+	lines := []string{}
+	for _, schema := range schemas {
+		def := ""
+		if schema.Default != nil {
+			def = fmt.Sprintf(" [default: %s]", *schema.Default)
+		}
+		lines = append(lines, fmt.Sprintf("%s* %s (%s)%s\n%s    %s", indent, schema.Name, schema.Type, def, indent, schema.Description))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ToJsonnet converts `raw`, the flag value supplied on the command line (or
+// entered interactively), into a Jsonnet literal appropriate for the
+// parameter's type. It is for single-valued parameter types (`Int`, `Bool`,
+// `String`); `StringSlice` and `Object` parameters are bound to repeatable
+// flags and must go through `ToJsonnetSlice` instead, so a literal comma
+// inside one element is never mistaken for a separator.
+func (schema ParamSchema) ToJsonnet(raw string) (string, error) {
+	switch schema.Type {
+	case Int, Bool:
+		// Both render as bare Jsonnet literals; the flag kind used to parse
+		// them already guaranteed `raw` is well-formed.
+		return raw, nil
+	case StringSlice, Object:
+		return schema.ToJsonnetSlice([]string{raw})
+	case String, "":
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("unknown parameter type '%s' for parameter '%s'", schema.Type, schema.Name)
+	}
+}
+
+// ToJsonnetSlice converts `parts`, the values collected by a repeated
+// `StringSlice`-bound flag (see `bindPrototypeFlag`), into a Jsonnet
+// literal. Taking the already-split slice -- rather than a comma-joined
+// string that gets re-split -- means an element containing a literal comma
+// (e.g. a `--tag` value of `"a,b"`) survives intact instead of being
+// shredded into extra elements.
+func (schema ParamSchema) ToJsonnetSlice(parts []string) (string, error) {
+	switch schema.Type {
+	case StringSlice:
+		quoted := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if part == "" {
+				continue
+			}
+			b, err := json.Marshal(part)
+			if err != nil {
+				return "", err
+			}
+			quoted = append(quoted, string(b))
+		}
+		return "[" + strings.Join(quoted, ", ") + "]", nil
+	case Object:
+		return objectToJsonnet(schema.Name, parts)
+	default:
+		return "", fmt.Errorf("parameter '%s' of type '%s' does not accept multiple values", schema.Name, schema.Type)
+	}
+}
+
+// objectToJsonnet converts the raw value(s) of an Object-typed parameter
+// into a Jsonnet object literal. A single element may be inline JSON or a
+// `@path/to/file.json` reference; one or more elements may instead each be
+// a `key=value` pair, as produced by a repeated `--flag key=value` binding.
+func objectToJsonnet(name string, parts []string) (string, error) {
+	if len(parts) == 1 {
+		raw := parts[0]
+		if strings.HasPrefix(raw, "@") {
+			data, err := ioutil.ReadFile(raw[1:])
+			if err != nil {
+				return "", fmt.Errorf("reading object parameter '%s' from '%s': %v", name, raw[1:], err)
+			}
+			raw = string(data)
+		}
+
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "{") {
+			var v interface{}
+			if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+				return "", fmt.Errorf("parameter '%s' is not valid JSON: %v", name, err)
+			}
+			return trimmed, nil
+		}
+		parts = []string{trimmed}
+	}
+
+	fields := map[string]string{}
+	for _, pair := range parts {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return "", fmt.Errorf("parameter '%s' expects 'key=value' pairs, got '%s'", name, pair)
+		}
+		fields[kv[0]] = kv[1]
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package prototype
+
+import "testing"
+
+func TestToJsonnet(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema ParamSchema
+		raw    string
+		want   string
+	}{
+		{"string", ParamSchema{Name: "name", Type: String}, "nginx", `"nginx"`},
+		{"untyped defaults to string", ParamSchema{Name: "name"}, "nginx", `"nginx"`},
+		{"int", ParamSchema{Name: "port", Type: Int}, "80", "80"},
+		{"bool", ParamSchema{Name: "tls", Type: Bool}, "true", "true"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.schema.ToJsonnet(c.raw)
+			if err != nil {
+				t.Fatalf("ToJsonnet(%q): unexpected error: %v", c.raw, err)
+			}
+			if got != c.want {
+				t.Errorf("ToJsonnet(%q) = %q, want %q", c.raw, got, c.want)
+			}
+		})
+	}
+}
+
+func TestToJsonnetSliceStringSlice(t *testing.T) {
+	schema := ParamSchema{Name: "tags", Type: StringSlice}
+
+	got, err := schema.ToJsonnetSlice([]string{"a,b", "c"})
+	if err != nil {
+		t.Fatalf("ToJsonnetSlice: unexpected error: %v", err)
+	}
+
+	// A literal comma inside an element must survive as part of that
+	// element, not be treated as a separator between two elements.
+	want := `["a,b", "c"]`
+	if got != want {
+		t.Errorf("ToJsonnetSlice([]string{\"a,b\", \"c\"}) = %q, want %q", got, want)
+	}
+}
+
+func TestToJsonnetObjectFromKeyValuePairs(t *testing.T) {
+	schema := ParamSchema{Name: "labels", Type: Object}
+
+	got, err := schema.ToJsonnetSlice([]string{"a=1", "b=2,3"})
+	if err != nil {
+		t.Fatalf("ToJsonnetSlice: unexpected error: %v", err)
+	}
+
+	// Object key/value pairs come from independent `--flag key=value`
+	// occurrences, not a comma-joined string, so a value containing a comma
+	// (e.g. `b=2,3`) must not be split into a separate pair.
+	if got != `{"a":"1","b":"2,3"}` {
+		t.Errorf("ToJsonnetSlice([]string{\"a=1\", \"b=2,3\"}) = %q", got)
+	}
+}
+
+func TestToJsonnetObjectFromInlineJSON(t *testing.T) {
+	schema := ParamSchema{Name: "config", Type: Object}
+
+	got, err := schema.ToJsonnetSlice([]string{`{"a": "b,c"}`})
+	if err != nil {
+		t.Fatalf("ToJsonnetSlice: unexpected error: %v", err)
+	}
+	if got != `{"a": "b,c"}` {
+		t.Errorf("ToJsonnetSlice(inline JSON) = %q, want the JSON echoed back unchanged", got)
+	}
+}
+
+func TestToJsonnetSliceRejectsSingleValuedTypes(t *testing.T) {
+	schema := ParamSchema{Name: "name", Type: String}
+
+	if _, err := schema.ToJsonnetSlice([]string{"a", "b"}); err == nil {
+		t.Error("ToJsonnetSlice on a String-typed parameter should error, not silently pick one value")
+	}
+}